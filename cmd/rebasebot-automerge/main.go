@@ -0,0 +1,133 @@
+// Command rebasebot-automerge polls a rebase pull request's required
+// status contexts and, once they're all green and the PR has no merge
+// conflicts, applies the configured merge labels or posts the
+// configured slash commands so tide or openshift-merge-bot's GitHub App
+// can take it the rest of the way. It also closes abandoned rebase PRs
+// that have gone stale, and serves Prometheus metrics alongside the
+// polling loop.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/automerge"
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/github"
+)
+
+// newGitHubClient builds the github.Client to poll with, based on
+// -auth-mode: "prow" authenticates with a static bot token (for repos
+// gated by Prow tide), "app" authenticates as a GitHub App installation
+// (for repos gated by openshift-merge-bot's GitHub-App flow).
+func newGitHubClient(authMode string, appID, appInstallationID int64, appPrivateKeyPath string) (github.Client, error) {
+	switch authMode {
+	case "prow":
+		return github.NewProwClient(os.Getenv("GITHUB_TOKEN")), nil
+	case "app":
+		key, err := os.ReadFile(appPrivateKeyPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading -app-private-key-path: %w", err)
+		}
+		return github.NewAppClient(appID, appInstallationID, key)
+	default:
+		return nil, fmt.Errorf("unknown -auth-mode %q, want \"prow\" or \"app\"", authMode)
+	}
+}
+
+// stringSliceFlag accumulates one value per flag occurrence, e.g.
+// `-merge-label lgtm -merge-label approved`.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string { return "" }
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func main() {
+	var (
+		org, repoName, headPrefix string
+		pr                        int
+		requiredContexts          stringSliceFlag
+		mergeLabels               stringSliceFlag
+		commentCommands           stringSliceFlag
+		pollInterval              time.Duration
+		backoffMax                time.Duration
+		staleAfter                time.Duration
+		metricsBindAddress        string
+		authMode                  string
+		appID, appInstallationID  int64
+		appPrivateKeyPath         string
+	)
+
+	flag.StringVar(&org, "org", "", "GitHub organization of the rebased repo")
+	flag.StringVar(&repoName, "repo", "", "GitHub repository name")
+	flag.IntVar(&pr, "pr", 0, "pull request number to hand off once green")
+	flag.StringVar(&headPrefix, "head-prefix", "rebase-", "head branch prefix identifying rebase PRs for the stale closer")
+	flag.Var(&requiredContexts, "required-context", "status context that must succeed before handoff (repeatable)")
+	flag.Var(&mergeLabels, "merge-label", "label to apply once the PR is green, e.g. lgtm (repeatable)")
+	flag.Var(&commentCommands, "comment-command", "slash command to post once the PR is green, e.g. /lgtm (repeatable)")
+	flag.DurationVar(&pollInterval, "poll-interval", time.Minute, "how often to re-check the PR")
+	flag.DurationVar(&backoffMax, "backoff-max", 30*time.Minute, "maximum poll interval after repeated check failures")
+	flag.DurationVar(&staleAfter, "stale-after", 0, "close other open rebase PRs (matched by -head-prefix, never -pr itself) that haven't been updated in this long; 0 disables")
+	flag.StringVar(&metricsBindAddress, "metrics-bind-address", ":8080", "address the /metrics endpoint is served on")
+	flag.StringVar(&authMode, "auth-mode", "prow", `how to authenticate to GitHub: "prow" (static bot token from GITHUB_TOKEN) or "app" (GitHub App installation)`)
+	flag.Int64Var(&appID, "app-id", 0, "GitHub App ID, required when -auth-mode=app")
+	flag.Int64Var(&appInstallationID, "app-installation-id", 0, "GitHub App installation ID, required when -auth-mode=app")
+	flag.StringVar(&appPrivateKeyPath, "app-private-key-path", "", "path to the GitHub App's PEM private key, required when -auth-mode=app")
+	flag.Parse()
+
+	log := logrus.NewEntry(logrus.StandardLogger())
+
+	if org == "" || repoName == "" || pr == 0 {
+		log.Fatal("-org, -repo and -pr are required")
+	}
+
+	ghClient, err := newGitHubClient(authMode, appID, appInstallationID, appPrivateKeyPath)
+	if err != nil {
+		log.WithError(err).Fatal("unable to build GitHub client")
+	}
+
+	// rebasebot-automerge isn't a Kubernetes controller: it only uses
+	// controller-runtime's manager to host the /metrics endpoint and
+	// the polling Runnable, so an empty rest.Config is enough.
+	mgr, err := ctrl.NewManager(&rest.Config{}, ctrl.Options{
+		MetricsBindAddress:     metricsBindAddress,
+		LeaderElection:         false,
+		HealthProbeBindAddress: "0",
+	})
+	if err != nil {
+		log.WithError(err).Fatal("unable to start manager")
+	}
+
+	poller := &automerge.Poller{
+		Client:     ghClient,
+		Org:        org,
+		Repo:       repoName,
+		PR:         pr,
+		HeadPrefix: headPrefix,
+		Config: automerge.Config{
+			RequiredContexts: requiredContexts,
+			MergeLabels:      mergeLabels,
+			CommentCommands:  commentCommands,
+			PollInterval:     pollInterval,
+			BackoffMax:       backoffMax,
+			StaleAfter:       staleAfter,
+		},
+		Log: log,
+	}
+	if err := mgr.Add(poller); err != nil {
+		log.WithError(err).Fatal("unable to register automerge poller")
+	}
+
+	if err := mgr.Start(ctrl.SetupSignalHandler()); err != nil {
+		log.WithError(err).Fatal("manager exited with error")
+	}
+}
@@ -0,0 +1,71 @@
+// Command rebasebot rebases a downstream fork branch onto an upstream
+// tag, automatically resolving the conflict classes it knows how to
+// handle (go.mod/go.sum, and so on).
+package main
+
+import (
+	"flag"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot"
+)
+
+// stringSliceFlag accumulates one value per flag occurrence, e.g.
+// `-module-denylist vendor/* -module-denylist third_party/*`.
+type stringSliceFlag []string
+
+func (s *stringSliceFlag) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringSliceFlag) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+func main() {
+	var opts rebasebot.Options
+	var allowlist, denylist stringSliceFlag
+
+	flag.StringVar(&opts.WorkDir, "workdir", "", "scratch directory to clone the downstream repo into")
+	flag.StringVar(&opts.SourceRemote, "source-remote", "origin", "git remote for the downstream fork")
+	flag.StringVar(&opts.SourceBranch, "source-branch", "", "downstream branch to rebase")
+	flag.StringVar(&opts.UpstreamRemote, "upstream-remote", "upstream", "git remote for the upstream project")
+	flag.StringVar(&opts.UpstreamTag, "upstream-tag", "", "upstream tag to rebase onto")
+	flag.Var(&allowlist, "module-allowlist", "glob of a module directory to rebase (repeatable); defaults to every module")
+	flag.Var(&denylist, "module-denylist", "glob of a module directory to skip, e.g. vendor/* (repeatable)")
+	flag.StringVar(&opts.ToolchainPolicy, "toolchain-policy", "prefer-upstream",
+		"how to reconcile a conflicting go directive: prefer-upstream, prefer-downstream, max, or pinned:<version>")
+	flag.StringVar(&opts.ToolchainCacheDir, "toolchain-cache-dir", "", "directory of pre-installed Go toolchains, one subdirectory per go<version>")
+	flag.BoolVar(&opts.DryRun, "dry-run", false, "stop before pushing the rebased branch")
+	flag.Parse()
+
+	opts.ModuleAllowlist = allowlist
+	opts.ModuleDenylist = denylist
+
+	log := logrus.NewEntry(logrus.StandardLogger())
+
+	if opts.WorkDir == "" || opts.SourceBranch == "" || opts.UpstreamTag == "" {
+		log.Fatal("-workdir, -source-branch and -upstream-tag are required")
+	}
+
+	result, err := rebasebot.Run(log, opts)
+	if err != nil {
+		log.WithError(err).Fatal("rebase failed")
+	}
+
+	for _, u := range result.UnresolvedConflicts {
+		log.Warn(u)
+	}
+	for dir, bumps := range result.ModuleBumps {
+		log.Infof("module %s:", dir)
+		for _, b := range bumps {
+			log.Infof("  %s", b)
+		}
+	}
+	for _, f := range result.DockerfilePatches {
+		log.Infof("patched golang base image in %s", f)
+	}
+}
@@ -0,0 +1,61 @@
+package automerge
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/github"
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/metrics"
+)
+
+// Check evaluates a single PR against cfg and, if it's green, hands it
+// off by applying cfg.MergeLabels and/or posting cfg.CommentCommands.
+// It reports whether the PR was handed off.
+func Check(gh github.Client, org, repo string, pr int, cfg Config) (bool, error) {
+	mergeable, err := gh.IsMergeable(org, repo, pr)
+	if err != nil {
+		return false, fmt.Errorf("checking mergeability of #%d: %w", pr, err)
+	}
+	if !mergeable {
+		return false, nil
+	}
+
+	statuses, err := gh.ListStatuses(org, repo, pr)
+	if err != nil {
+		return false, fmt.Errorf("listing statuses for #%d: %w", pr, err)
+	}
+	if !allRequiredSucceeded(statuses, cfg.RequiredContexts) {
+		return false, nil
+	}
+
+	if len(cfg.MergeLabels) > 0 {
+		if err := gh.AddLabels(org, repo, pr, cfg.MergeLabels...); err != nil {
+			return false, fmt.Errorf("labeling #%d: %w", pr, err)
+		}
+	}
+	if len(cfg.CommentCommands) > 0 {
+		if err := gh.CreateComment(org, repo, pr, strings.Join(cfg.CommentCommands, "\n")); err != nil {
+			return false, fmt.Errorf("commenting on #%d: %w", pr, err)
+		}
+	}
+
+	metrics.RebasePRsMerged.Inc()
+	if prInfo, err := gh.GetPullRequest(org, repo, pr); err == nil {
+		metrics.TimeToMergeSeconds.Observe(time.Since(prInfo.CreatedAt).Seconds())
+	}
+	return true, nil
+}
+
+func allRequiredSucceeded(statuses []github.Status, required []string) bool {
+	seen := make(map[string]string, len(statuses))
+	for _, s := range statuses {
+		seen[s.Context] = s.State
+	}
+	for _, ctx := range required {
+		if seen[ctx] != "success" {
+			return false
+		}
+	}
+	return true
+}
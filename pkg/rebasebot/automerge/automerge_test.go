@@ -0,0 +1,80 @@
+package automerge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/github"
+)
+
+func TestCheck(t *testing.T) {
+	cfg := Config{
+		RequiredContexts: []string{"ci/build"},
+		MergeLabels:      []string{"lgtm"},
+		CommentCommands:  []string{"/lgtm"},
+	}
+
+	tests := []struct {
+		name          string
+		client        *fakeClient
+		wantHandedOff bool
+	}{
+		{
+			name: "not mergeable",
+			client: &fakeClient{
+				mergeable: false,
+				statuses:  []github.Status{{Context: "ci/build", State: "success"}},
+			},
+			wantHandedOff: false,
+		},
+		{
+			name: "required context missing",
+			client: &fakeClient{
+				mergeable: true,
+				statuses:  []github.Status{{Context: "ci/other", State: "success"}},
+			},
+			wantHandedOff: false,
+		},
+		{
+			name: "required context pending",
+			client: &fakeClient{
+				mergeable: true,
+				statuses:  []github.Status{{Context: "ci/build", State: "pending"}},
+			},
+			wantHandedOff: false,
+		},
+		{
+			name: "green",
+			client: &fakeClient{
+				mergeable: true,
+				statuses:  []github.Status{{Context: "ci/build", State: "success"}},
+				prs:       []github.PullRequest{{Number: 42, CreatedAt: time.Now().Add(-time.Hour)}},
+			},
+			wantHandedOff: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handedOff, err := Check(tt.client, "org", "repo", 42, cfg)
+			if err != nil {
+				t.Fatalf("Check() error: %v", err)
+			}
+			if handedOff != tt.wantHandedOff {
+				t.Errorf("Check() = %v, want %v", handedOff, tt.wantHandedOff)
+			}
+			if tt.wantHandedOff {
+				if len(tt.client.labelsAdded) != 1 || tt.client.labelsAdded[0] != "lgtm" {
+					t.Errorf("labelsAdded = %v, want [lgtm]", tt.client.labelsAdded)
+				}
+				if len(tt.client.commentsCreated) != 1 || tt.client.commentsCreated[0] != "/lgtm" {
+					t.Errorf("commentsCreated = %v, want [/lgtm]", tt.client.commentsCreated)
+				}
+			} else {
+				if len(tt.client.labelsAdded) != 0 {
+					t.Errorf("labelsAdded = %v, want none", tt.client.labelsAdded)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+package automerge
+
+import "time"
+
+// Backoff doubles its interval on every call, up to a configured
+// ceiling, so repeated polling failures don't hammer the GitHub API.
+type Backoff struct {
+	initial  time.Duration
+	interval time.Duration
+	max      time.Duration
+}
+
+// NewBackoff creates a Backoff starting at initial and capped at max.
+func NewBackoff(initial, max time.Duration) *Backoff {
+	return &Backoff{initial: initial, interval: initial, max: max}
+}
+
+// Next returns the current interval and grows it for next time.
+func (b *Backoff) Next() time.Duration {
+	interval := b.interval
+	b.interval *= 2
+	if b.max > 0 && b.interval > b.max {
+		b.interval = b.max
+	}
+	return interval
+}
+
+// Reset restores the backoff to its initial interval, e.g. after a
+// successful check.
+func (b *Backoff) Reset() {
+	b.interval = b.initial
+}
@@ -0,0 +1,40 @@
+package automerge
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBackoffNext(t *testing.T) {
+	b := NewBackoff(time.Second, 8*time.Second)
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 8 * time.Second}
+	for i, w := range want {
+		if got := b.Next(); got != w {
+			t.Errorf("Next() call %d = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestBackoffNextUncapped(t *testing.T) {
+	b := NewBackoff(time.Second, 0)
+
+	want := []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}
+	for i, w := range want {
+		if got := b.Next(); got != w {
+			t.Errorf("Next() call %d = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestBackoffReset(t *testing.T) {
+	b := NewBackoff(time.Second, time.Minute)
+
+	b.Next()
+	b.Next()
+	b.Reset()
+
+	if got := b.Next(); got != time.Second {
+		t.Errorf("Next() after Reset() = %v, want %v", got, time.Second)
+	}
+}
@@ -0,0 +1,40 @@
+// Package automerge polls a rebase pull request's required status
+// contexts and, once they're all green and the PR has no merge
+// conflicts, hands it off for merge via labels or slash commands, so a
+// human no longer has to shepherd every rebase PR through tide or
+// openshift-merge-bot by hand. It also closes abandoned rebase PRs that
+// have gone stale.
+package automerge
+
+import "time"
+
+// Config controls how a PR is judged ready to hand off, and how it's
+// handed off. The same binary serves repos gated by Prow tide
+// (MergeLabels: "lgtm", "approved", "tide/merge-method-squash") and
+// repos gated by openshift-merge-bot's GitHub-App flow
+// (CommentCommands: "/lgtm", "/approve"), since which one applies is
+// purely a matter of what's configured.
+type Config struct {
+	// RequiredContexts lists the commit status contexts (e.g. Prow jobs)
+	// that must all report success before a PR is handed off. Only
+	// commit statuses are consulted; GitHub Actions check runs are not.
+	RequiredContexts []string
+
+	// MergeLabels are applied to the PR once it's ready.
+	MergeLabels []string
+
+	// CommentCommands are posted as a single comment once the PR is
+	// ready, for bots gated by slash commands instead of labels.
+	CommentCommands []string
+
+	// PollInterval is how often a pending PR is re-checked.
+	PollInterval time.Duration
+
+	// BackoffMax caps how long PollInterval is allowed to grow to after
+	// repeated check failures.
+	BackoffMax time.Duration
+
+	// StaleAfter closes an open rebase PR that hasn't been updated for
+	// this long. Zero disables the stale-PR closer.
+	StaleAfter time.Duration
+}
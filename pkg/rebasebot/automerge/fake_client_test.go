@@ -0,0 +1,59 @@
+package automerge
+
+import (
+	"fmt"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/github"
+)
+
+// fakeClient is a minimal in-memory github.Client for testing Check and
+// CloseStale without a real GitHub API.
+type fakeClient struct {
+	mergeable bool
+	statuses  []github.Status
+	prs       []github.PullRequest
+
+	labelsAdded     []string
+	commentsCreated []string
+	closed          []int
+}
+
+func (f *fakeClient) ListStatuses(org, repo string, pr int) ([]github.Status, error) {
+	return f.statuses, nil
+}
+
+func (f *fakeClient) IsMergeable(org, repo string, pr int) (bool, error) {
+	return f.mergeable, nil
+}
+
+func (f *fakeClient) AddLabels(org, repo string, pr int, labels ...string) error {
+	f.labelsAdded = append(f.labelsAdded, labels...)
+	return nil
+}
+
+func (f *fakeClient) RemoveLabels(org, repo string, pr int, labels ...string) error {
+	return nil
+}
+
+func (f *fakeClient) CreateComment(org, repo string, pr int, body string) error {
+	f.commentsCreated = append(f.commentsCreated, body)
+	return nil
+}
+
+func (f *fakeClient) ClosePR(org, repo string, pr int) error {
+	f.closed = append(f.closed, pr)
+	return nil
+}
+
+func (f *fakeClient) GetPullRequest(org, repo string, pr int) (github.PullRequest, error) {
+	for _, p := range f.prs {
+		if p.Number == pr {
+			return p, nil
+		}
+	}
+	return github.PullRequest{}, fmt.Errorf("no such PR #%d", pr)
+}
+
+func (f *fakeClient) ListOpenRebasePRs(org, repo, headPrefix string) ([]github.PullRequest, error) {
+	return f.prs, nil
+}
@@ -0,0 +1,50 @@
+package automerge
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/github"
+)
+
+// Poller implements controller-runtime's manager.Runnable, periodically
+// checking a single rebase PR against Config until it's handed off for
+// merge or the manager is stopped.
+type Poller struct {
+	Client     github.Client
+	Org        string
+	Repo       string
+	PR         int
+	HeadPrefix string
+	Config     Config
+	Log        *logrus.Entry
+}
+
+// Start implements manager.Runnable.
+func (p *Poller) Start(ctx context.Context) error {
+	backoff := NewBackoff(p.Config.PollInterval, p.Config.BackoffMax)
+
+	for {
+		merged, err := Check(p.Client, p.Org, p.Repo, p.PR, p.Config)
+		switch {
+		case err != nil:
+			p.Log.WithError(err).Warn("automerge check failed, backing off")
+		case merged:
+			return nil
+		default:
+			backoff.Reset()
+		}
+
+		if err := CloseStale(p.Client, p.Org, p.Repo, p.HeadPrefix, p.Config, time.Now(), p.PR); err != nil {
+			p.Log.WithError(err).Warn("stale-PR closer failed")
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff.Next()):
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package automerge
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/github"
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/metrics"
+)
+
+// CloseStale closes every open rebase PR under headPrefix that hasn't
+// been updated within cfg.StaleAfter, so abandoned rebase attempts
+// don't accumulate. It is a no-op when cfg.StaleAfter is zero. PRs
+// listed in exclude are left alone even if stale, so a poller sweeping
+// repo-wide never closes the very PR it's still legitimately
+// shepherding through CI.
+func CloseStale(gh github.Client, org, repo, headPrefix string, cfg Config, now time.Time, exclude ...int) error {
+	if cfg.StaleAfter == 0 {
+		return nil
+	}
+
+	excluded := make(map[int]bool, len(exclude))
+	for _, pr := range exclude {
+		excluded[pr] = true
+	}
+
+	prs, err := gh.ListOpenRebasePRs(org, repo, headPrefix)
+	if err != nil {
+		return fmt.Errorf("listing open rebase PRs: %w", err)
+	}
+
+	for _, pr := range prs {
+		if excluded[pr.Number] {
+			continue
+		}
+		if now.Sub(pr.UpdatedAt) < cfg.StaleAfter {
+			continue
+		}
+		if err := gh.ClosePR(org, repo, pr.Number); err != nil {
+			return fmt.Errorf("closing stale PR #%d: %w", pr.Number, err)
+		}
+		metrics.StalePRsClosed.Inc()
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package automerge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/github"
+)
+
+func TestCloseStale(t *testing.T) {
+	now := time.Now()
+	cfg := Config{StaleAfter: 24 * time.Hour}
+
+	client := &fakeClient{
+		prs: []github.PullRequest{
+			{Number: 1, UpdatedAt: now.Add(-48 * time.Hour)}, // stale
+			{Number: 2, UpdatedAt: now.Add(-time.Hour)},      // fresh
+		},
+	}
+
+	if err := CloseStale(client, "org", "repo", "rebase-", cfg, now); err != nil {
+		t.Fatalf("CloseStale() error: %v", err)
+	}
+
+	if len(client.closed) != 1 || client.closed[0] != 1 {
+		t.Errorf("closed = %v, want [1]", client.closed)
+	}
+}
+
+func TestCloseStaleExcludesGivenPRs(t *testing.T) {
+	now := time.Now()
+	cfg := Config{StaleAfter: 24 * time.Hour}
+
+	client := &fakeClient{
+		prs: []github.PullRequest{
+			{Number: 1, UpdatedAt: now.Add(-48 * time.Hour)}, // stale, but excluded
+			{Number: 2, UpdatedAt: now.Add(-48 * time.Hour)}, // stale
+		},
+	}
+
+	if err := CloseStale(client, "org", "repo", "rebase-", cfg, now, 1); err != nil {
+		t.Fatalf("CloseStale() error: %v", err)
+	}
+
+	if len(client.closed) != 1 || client.closed[0] != 2 {
+		t.Errorf("closed = %v, want [2] (PR #1 is excluded)", client.closed)
+	}
+}
+
+func TestCloseStaleDisabled(t *testing.T) {
+	now := time.Now()
+	cfg := Config{StaleAfter: 0}
+
+	client := &fakeClient{
+		prs: []github.PullRequest{
+			{Number: 1, UpdatedAt: now.Add(-24 * 365 * time.Hour)},
+		},
+	}
+
+	if err := CloseStale(client, "org", "repo", "rebase-", cfg, now); err != nil {
+		t.Fatalf("CloseStale() error: %v", err)
+	}
+
+	if len(client.closed) != 0 {
+		t.Errorf("closed = %v, want none (StaleAfter disabled)", client.closed)
+	}
+}
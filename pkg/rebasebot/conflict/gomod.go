@@ -0,0 +1,228 @@
+package conflict
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"golang.org/x/mod/modfile"
+	"golang.org/x/mod/semver"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/toolchain"
+)
+
+// GoModResolver reconciles conflicted go.mod files left over after the
+// default 3-way merge. It takes the union of the require blocks from
+// both sides, preferring whichever side pins the higher semver for a
+// shared module, and keeps every replace/exclude directive that only
+// exists on the downstream side (our carries). go.sum is regenerated
+// afterwards by running `go mod tidy` followed by `go mod download`
+// inside a sandboxed GOPATH so the merged go.mod never ends up out of
+// sync with its checksums.
+type GoModResolver struct {
+	// GoBin is the go binary to invoke for tidy/download. Defaults to
+	// "go" when empty.
+	GoBin string
+
+	// ToolchainPolicy decides which `go` directive version wins when
+	// upstream and downstream disagree. The zero value prefers
+	// upstream's version, matching a plain 3-way merge.
+	ToolchainPolicy toolchain.Policy
+
+	// ToolchainCacheDir is an optional directory of pre-installed Go
+	// toolchains (one subdirectory per "go<version>") consulted before
+	// falling back to a `toolchain` directive.
+	ToolchainCacheDir string
+}
+
+// CanResolve claims any go.mod file.
+func (r *GoModResolver) CanResolve(path string) bool {
+	return filepath.Base(path) == "go.mod"
+}
+
+// Resolve merges the two conflicting sides of path and regenerates
+// go.sum to match.
+func (r *GoModResolver) Resolve(repoDir, path, ours, theirs string) (Result, error) {
+	oursContent, err := gitShow(repoDir, ours, path)
+	if err != nil {
+		return Result{}, fmt.Errorf("reading our side of %s: %w", path, err)
+	}
+	theirsContent, err := gitShow(repoDir, theirs, path)
+	if err != nil {
+		return Result{}, fmt.Errorf("reading their side of %s: %w", path, err)
+	}
+
+	oursFile, err := modfile.Parse(path, oursContent, nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("parsing our side of %s: %w", path, err)
+	}
+	theirsFile, err := modfile.Parse(path, theirsContent, nil)
+	if err != nil {
+		return Result{}, fmt.Errorf("parsing their side of %s: %w", path, err)
+	}
+
+	merged := &modfile.File{}
+	if err := merged.AddModuleStmt(oursFile.Module.Mod.Path); err != nil {
+		return Result{}, fmt.Errorf("setting module path for %s: %w", path, err)
+	}
+
+	goVersion := r.ToolchainPolicy.Resolve(goDirective(oursFile), goDirective(theirsFile))
+	if goVersion != "" {
+		if err := merged.AddGoStmt(goVersion); err != nil {
+			return Result{}, fmt.Errorf("setting go directive for %s: %w", path, err)
+		}
+	}
+
+	// Start from upstream's requirements and let downstream win ties,
+	// since a downstream carry usually exists for a reason.
+	requires := map[string]string{}
+	for _, req := range theirsFile.Require {
+		requires[req.Mod.Path] = req.Mod.Version
+	}
+
+	var unresolved []string
+	for _, req := range oursFile.Require {
+		upstreamVersion, known := requires[req.Mod.Path]
+		switch {
+		case !known:
+			requires[req.Mod.Path] = req.Mod.Version
+		case majorVersion(req.Mod.Version) != majorVersion(upstreamVersion):
+			// Checked before the semver tie-break below: a major-version
+			// divergence must always be surfaced, even when downstream's
+			// version is semver-higher than upstream's (e.g. a downstream
+			// carry bumping v0.x to v1.x).
+			unresolved = append(unresolved, fmt.Sprintf(
+				"%s: downstream wants %s, upstream wants %s (diverging major version, kept upstream)",
+				req.Mod.Path, req.Mod.Version, upstreamVersion))
+		case semverGreaterOrEqual(req.Mod.Version, upstreamVersion):
+			requires[req.Mod.Path] = req.Mod.Version
+		}
+	}
+	for modPath, version := range requires {
+		if err := merged.AddRequire(modPath, version); err != nil {
+			return Result{}, fmt.Errorf("adding require %s %s: %w", modPath, version, err)
+		}
+	}
+
+	// Downstream-only replace/exclude directives (our carries, like the
+	// client-go pin) always win: upstream has no opinion on them.
+	for _, rep := range oursFile.Replace {
+		if err := merged.AddReplace(rep.Old.Path, rep.Old.Version, rep.New.Path, rep.New.Version); err != nil {
+			return Result{}, fmt.Errorf("adding replace %s: %w", rep.Old.Path, err)
+		}
+	}
+	for _, excl := range oursFile.Exclude {
+		if err := merged.AddExclude(excl.Mod.Path, excl.Mod.Version); err != nil {
+			return Result{}, fmt.Errorf("adding exclude %s: %w", excl.Mod.Path, err)
+		}
+	}
+
+	merged.Cleanup()
+	out, err := merged.Format()
+	if err != nil {
+		return Result{}, fmt.Errorf("formatting merged %s: %w", path, err)
+	}
+
+	fullPath := filepath.Join(repoDir, path)
+	if err := os.WriteFile(fullPath, out, 0o644); err != nil {
+		return Result{}, fmt.Errorf("writing merged %s: %w", path, err)
+	}
+
+	var goBin string
+	if goVersion != "" {
+		goBin, err = toolchain.Apply(fullPath, goVersion, r.ToolchainCacheDir)
+		if err != nil {
+			return Result{}, fmt.Errorf("reconciling go toolchain for %s: %w", path, err)
+		}
+	}
+
+	if err := r.tidyAndDownload(filepath.Dir(fullPath), goBin); err != nil {
+		return Result{}, fmt.Errorf("regenerating go.sum for %s: %w", path, err)
+	}
+
+	return Result{
+		Resolved:      true,
+		Unresolved:    joinUnresolved(unresolved),
+		GoVersion:     goVersion,
+		ExtraResolved: []string{filepath.Join(filepath.Dir(path), "go.sum")},
+	}, nil
+}
+
+// goDirective returns a go.mod's `go` directive version, or "" if it
+// doesn't have one.
+func goDirective(f *modfile.File) string {
+	if f.Go == nil {
+		return ""
+	}
+	return f.Go.Version
+}
+
+// tidyAndDownload runs `go mod tidy` followed by `go mod download` in a
+// sandboxed GOPATH so the merge never pollutes the caller's module
+// cache or depends on state outside the two sides already being merged.
+// goBinOverride, if non-empty, takes precedence over r.GoBin; it's how
+// a toolchain.Apply cache hit gets actually used instead of whatever
+// `go` is on $PATH.
+func (r *GoModResolver) tidyAndDownload(moduleDir, goBinOverride string) error {
+	goBin := goBinOverride
+	if goBin == "" {
+		goBin = r.GoBin
+	}
+	if goBin == "" {
+		goBin = "go"
+	}
+
+	sandbox, err := os.MkdirTemp("", "rebasebot-gopath-")
+	if err != nil {
+		return fmt.Errorf("creating sandbox GOPATH: %w", err)
+	}
+	defer os.RemoveAll(sandbox)
+
+	env := append(os.Environ(), "GOPATH="+sandbox, "GOFLAGS=-mod=mod")
+
+	for _, args := range [][]string{{"mod", "tidy"}, {"mod", "download"}} {
+		cmd := exec.Command(goBin, args...)
+		cmd.Dir = moduleDir
+		cmd.Env = env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("%s %v: %w\n%s", goBin, args, err, out)
+		}
+	}
+	return nil
+}
+
+func semverGreaterOrEqual(a, b string) bool {
+	return semver.Compare(canonical(a), canonical(b)) >= 0
+}
+
+func majorVersion(v string) string {
+	return semver.Major(canonical(v))
+}
+
+// canonical coerces a (possibly pseudo-version) module version into
+// something golang.org/x/mod/semver will accept, since it requires the
+// leading "v".
+func canonical(v string) string {
+	if semver.IsValid(v) {
+		return v
+	}
+	return "v" + v
+}
+
+func joinUnresolved(lines []string) string {
+	if len(lines) == 0 {
+		return ""
+	}
+	msg := "semantic go.mod conflicts require manual review:\n"
+	for _, l := range lines {
+		msg += "- " + l + "\n"
+	}
+	return msg
+}
+
+func gitShow(repoDir, ref, path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", ref+":"+path)
+	cmd.Dir = repoDir
+	return cmd.Output()
+}
@@ -0,0 +1,225 @@
+package conflict
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/toolchain"
+)
+
+// fakemodGoMod is a trivial local module used as the replace target for
+// a module whose major version diverges between ours and theirs, so the
+// merge never needs to reach the network to resolve it.
+const fakemodGoMod = "module example.com/fakemod\n\ngo 1.20\n"
+
+const fakemodSource = "package fakemod\n"
+
+const oursGoMod = `module example.com/test
+
+go 1.20
+
+require (
+	github.com/sirupsen/logrus v1.8.1
+	example.com/fakemod v1.5.0
+)
+
+replace example.com/fakemod => ./local/fakemod
+`
+
+const theirsGoMod = `module example.com/test
+
+go 1.21
+
+require (
+	github.com/sirupsen/logrus v1.8.0
+	example.com/fakemod v2.0.0+incompatible
+)
+`
+
+const testMain = "package main\n\nimport _ \"github.com/sirupsen/logrus\"\n\nfunc main() {}\n"
+
+// newConflictRepo builds a git repo with a "theirs" branch (upstream)
+// and an "ours" branch (downstream), each with its own go.mod content,
+// so GoModResolver.Resolve can be exercised against real refs.
+func newConflictRepo(t *testing.T, oursGoMod, theirsGoMod string) string {
+	t.Helper()
+	dir := t.TempDir()
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v\n%s", args, err, out)
+		}
+	}
+
+	run("init", "-q")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "test")
+
+	write := func(path, content string) {
+		t.Helper()
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatalf("mkdir %s: %v", filepath.Dir(full), err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatalf("write %s: %v", path, err)
+		}
+	}
+
+	write("go.mod", theirsGoMod)
+	write("main.go", testMain)
+	run("add", "-A")
+	run("commit", "-q", "-m", "base")
+	run("branch", "theirs")
+
+	run("checkout", "-q", "-b", "ours")
+	write("go.mod", oursGoMod)
+	write("local/fakemod/go.mod", fakemodGoMod)
+	write("local/fakemod/fakemod.go", fakemodSource)
+	run("add", "-A")
+	run("commit", "-q", "-m", "downstream carries")
+
+	return dir
+}
+
+func TestGoModResolverResolve(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go binary not available")
+	}
+
+	repoDir := newConflictRepo(t, oursGoMod, theirsGoMod)
+
+	r := &GoModResolver{}
+	result, err := r.Resolve(repoDir, "go.mod", "ours", "theirs")
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+
+	if !result.Resolved {
+		t.Errorf("result.Resolved = false, want true")
+	}
+
+	// Upstream's go directive wins, matching the zero-value
+	// PreferUpstream toolchain policy.
+	if result.GoVersion != "1.21" {
+		t.Errorf("result.GoVersion = %q, want %q", result.GoVersion, "1.21")
+	}
+
+	// The diverging-major-version module is reported as unresolved
+	// rather than silently picking a side.
+	if !strings.Contains(result.Unresolved, "example.com/fakemod") {
+		t.Errorf("result.Unresolved = %q, want it to mention example.com/fakemod", result.Unresolved)
+	}
+
+	// go.sum is regenerated alongside go.mod and must be reported so the
+	// caller knows to stage it too.
+	if !containsString(result.ExtraResolved, "go.sum") {
+		t.Errorf("result.ExtraResolved = %v, want it to include go.sum", result.ExtraResolved)
+	}
+
+	merged, err := os.ReadFile(filepath.Join(repoDir, "go.mod"))
+	if err != nil {
+		t.Fatalf("reading merged go.mod: %v", err)
+	}
+
+	// The higher, non-diverging logrus version (ours) wins the tie-break.
+	if !strings.Contains(string(merged), "github.com/sirupsen/logrus v1.8.1") {
+		t.Errorf("merged go.mod = %q, want it to keep logrus v1.8.1", merged)
+	}
+}
+
+func TestGoModResolverResolveHonorsToolchainPolicy(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go binary not available")
+	}
+
+	repoDir := newConflictRepo(t, oursGoMod, theirsGoMod)
+
+	r := &GoModResolver{ToolchainPolicy: toolchain.Policy{}}
+	policy, err := toolchain.ParsePolicy("prefer-downstream")
+	if err != nil {
+		t.Fatalf("ParsePolicy: %v", err)
+	}
+	r.ToolchainPolicy = policy
+
+	result, err := r.Resolve(repoDir, "go.mod", "ours", "theirs")
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+
+	if result.GoVersion != "1.20" {
+		t.Errorf("result.GoVersion = %q, want %q (downstream's go directive)", result.GoVersion, "1.20")
+	}
+}
+
+// TestGoModResolverResolveDivergingMajorDownstreamAhead guards against
+// the major-version check being skipped whenever downstream's version
+// happens to be semver-higher than upstream's (e.g. a downstream carry
+// bumping v0.x to v1.x): that's still a major-version divergence and
+// must be reported, not silently merged.
+func TestGoModResolverResolveDivergingMajorDownstreamAhead(t *testing.T) {
+	if _, err := exec.LookPath("go"); err != nil {
+		t.Skip("go binary not available")
+	}
+
+	ours := `module example.com/test
+
+go 1.20
+
+require (
+	github.com/sirupsen/logrus v1.8.1
+	example.com/fakemod v1.2.0
+)
+
+replace example.com/fakemod => ./local/fakemod
+`
+	theirs := `module example.com/test
+
+go 1.21
+
+require (
+	github.com/sirupsen/logrus v1.8.1
+	example.com/fakemod v0.9.0+incompatible
+)
+`
+
+	repoDir := newConflictRepo(t, ours, theirs)
+
+	r := &GoModResolver{}
+	result, err := r.Resolve(repoDir, "go.mod", "ours", "theirs")
+	if err != nil {
+		t.Fatalf("Resolve() error: %v", err)
+	}
+
+	if !strings.Contains(result.Unresolved, "example.com/fakemod") {
+		t.Errorf("result.Unresolved = %q, want it to report the diverging major version even though downstream is semver-ahead", result.Unresolved)
+	}
+}
+
+func containsString(ss []string, want string) bool {
+	for _, s := range ss {
+		if s == want {
+			return true
+		}
+	}
+	return false
+}
+
+func TestGoModResolverCanResolve(t *testing.T) {
+	r := &GoModResolver{}
+	if !r.CanResolve("go.mod") {
+		t.Errorf("CanResolve(%q) = false, want true", "go.mod")
+	}
+	if !r.CanResolve("nested/go.mod") {
+		t.Errorf("CanResolve(%q) = false, want true", "nested/go.mod")
+	}
+	if r.CanResolve("go.sum") {
+		t.Errorf("CanResolve(%q) = true, want false", "go.sum")
+	}
+}
@@ -0,0 +1,68 @@
+// Package conflict provides pluggable strategies for automatically
+// resolving merge conflicts that rebasebot's default 3-way merge leaves
+// behind, for file types where upstream and downstream changes can
+// usually be reconciled mechanically (go.mod, vendor/modules.txt,
+// OWNERS, ...).
+package conflict
+
+// Resolver implements a strategy for automatically resolving a specific
+// class of merge conflict.
+type Resolver interface {
+	// CanResolve reports whether this resolver knows how to handle a
+	// conflict in the given repo-relative path.
+	CanResolve(path string) bool
+
+	// Resolve attempts to reconcile the conflict for path inside
+	// repoDir. ours/theirs are the refs of the two sides being merged
+	// (typically the downstream branch and the upstream tag). It
+	// returns an error only for unexpected failures; a conflict the
+	// resolver understands but cannot safely reconcile is reported
+	// through Result.Unresolved instead of failing the rebase.
+	Resolve(repoDir, path, ours, theirs string) (Result, error)
+}
+
+// Result is what a Resolver produced for a single conflicted path.
+type Result struct {
+	// Resolved is true if the conflict markers were removed and path is
+	// ready to be git-added.
+	Resolved bool
+
+	// Unresolved, when non-empty, describes a semantic conflict the
+	// resolver detected but could not safely reconcile (e.g. diverging
+	// major versions). It is reported back to the caller instead of
+	// failing the rebase outright; the rebasebot command logs it as a
+	// warning and does not post it as a PR comment.
+	Unresolved string
+
+	// GoVersion is set by resolvers that reconcile a `go` directive, to
+	// the version they settled on, so callers can propagate it to
+	// related artifacts (e.g. Dockerfile base images).
+	GoVersion string
+
+	// ExtraResolved lists repo-relative paths, beyond the one passed to
+	// Resolve, that the resolver also fixed up and that the caller must
+	// stage too (e.g. go.sum, regenerated alongside a merged go.mod).
+	ExtraResolved []string
+}
+
+// registry is the set of resolvers consulted, in order, for every
+// conflicted path left over after the default 3-way merge.
+var registry []Resolver
+
+// Register adds a Resolver to the default registry. Resolvers are tried
+// in registration order; the first one whose CanResolve returns true
+// owns the path.
+func Register(r Resolver) {
+	registry = append(registry, r)
+}
+
+// For returns the registered Resolver that claims path, or nil if none
+// of them can handle it.
+func For(path string) Resolver {
+	for _, r := range registry {
+		if r.CanResolve(path) {
+			return r
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package rebasebot
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// runGit runs git with args inside dir and returns trimmed stdout. Stderr
+// is folded into the returned error so callers get useful context without
+// having to thread a logger through every call site.
+func runGit(dir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, stderr.String())
+	}
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// clone does a full clone of repoURL into dir and checks out branch.
+func clone(dir, repoURL, branch string) error {
+	_, err := runGit("", "clone", "--branch", branch, repoURL, dir)
+	return err
+}
+
+// fetchTag fetches a single tag from remote so it can be merged via
+// FETCH_HEAD.
+func fetchTag(dir, remote, tag string) error {
+	_, err := runGit(dir, "fetch", remote, "tag", tag)
+	return err
+}
+
+// merge attempts a 3-way merge of ref into the current branch without
+// committing, so the caller can inspect and resolve conflicts first.
+func merge(dir, ref string) error {
+	_, err := runGit(dir, "merge", "--no-commit", "--no-ff", ref)
+	return err
+}
+
+// conflictedFiles returns the repo-relative paths that are still
+// unmerged after a conflicting merge.
+func conflictedFiles(dir string) ([]string, error) {
+	out, err := runGit(dir, "diff", "--name-only", "--diff-filter=U")
+	if err != nil {
+		return nil, err
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+func add(dir string, paths ...string) error {
+	args := append([]string{"add"}, paths...)
+	_, err := runGit(dir, args...)
+	return err
+}
+
+func commit(dir, message string) error {
+	_, err := runGit(dir, "commit", "-m", message)
+	return err
+}
+
+func push(dir, remote, branch string) error {
+	_, err := runGit(dir, "push", remote, branch)
+	return err
+}
+
+// gitShowBytes returns the raw contents of path as it exists at ref,
+// without the trimming runGit applies to text output.
+func gitShowBytes(dir, ref, path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", ref+":"+path)
+	cmd.Dir = dir
+	return cmd.Output()
+}
@@ -0,0 +1,302 @@
+package github
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const appAPIBaseURL = "https://api.github.com"
+
+// AppClient implements Client by authenticating as a GitHub App
+// installation, for repos gated by openshift-merge-bot's GitHub-App
+// flow rather than Prow tide.
+type AppClient struct {
+	appID          int64
+	installationID int64
+	privateKey     *rsa.PrivateKey
+	httpClient     *http.Client
+	baseURL        string
+
+	mu              sync.Mutex
+	installToken    string
+	installTokenExp time.Time
+}
+
+// NewAppClient builds an AppClient from a GitHub App's ID, the target
+// installation ID, and the App's PEM-encoded private key.
+func NewAppClient(appID, installationID int64, privateKeyPEM []byte) (*AppClient, error) {
+	block, _ := pem.Decode(privateKeyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM block found in GitHub App private key")
+	}
+	key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing GitHub App private key: %w", err)
+	}
+
+	return &AppClient{
+		appID:          appID,
+		installationID: installationID,
+		privateKey:     key,
+		httpClient:     &http.Client{Timeout: 30 * time.Second},
+		baseURL:        appAPIBaseURL,
+	}, nil
+}
+
+func (c *AppClient) ListStatuses(org, repo string, pr int) ([]Status, error) {
+	prInfo, err := c.getPullRequest(org, repo, pr)
+	if err != nil {
+		return nil, err
+	}
+
+	var resp struct {
+		Statuses []struct {
+			Context string `json:"context"`
+			State   string `json:"state"`
+		} `json:"statuses"`
+	}
+	path := fmt.Sprintf("/repos/%s/%s/commits/%s/status", org, repo, prInfo.Head.SHA)
+	if err := c.do(http.MethodGet, path, nil, &resp); err != nil {
+		return nil, fmt.Errorf("listing statuses for %s: %w", prInfo.Head.SHA, err)
+	}
+
+	out := make([]Status, 0, len(resp.Statuses))
+	for _, s := range resp.Statuses {
+		out = append(out, Status{Context: s.Context, State: s.State})
+	}
+	return out, nil
+}
+
+func (c *AppClient) IsMergeable(org, repo string, pr int) (bool, error) {
+	prInfo, err := c.getPullRequest(org, repo, pr)
+	if err != nil {
+		return false, err
+	}
+	return prInfo.Mergeable == nil || *prInfo.Mergeable, nil
+}
+
+func (c *AppClient) AddLabels(org, repo string, pr int, labels ...string) error {
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/labels", org, repo, pr)
+	if err := c.do(http.MethodPost, path, map[string][]string{"labels": labels}, nil); err != nil {
+		return fmt.Errorf("adding labels to #%d: %w", pr, err)
+	}
+	return nil
+}
+
+func (c *AppClient) RemoveLabels(org, repo string, pr int, labels ...string) error {
+	for _, label := range labels {
+		path := fmt.Sprintf("/repos/%s/%s/issues/%d/labels/%s", org, repo, pr, label)
+		if err := c.do(http.MethodDelete, path, nil, nil); err != nil {
+			return fmt.Errorf("removing label %q from #%d: %w", label, pr, err)
+		}
+	}
+	return nil
+}
+
+func (c *AppClient) CreateComment(org, repo string, pr int, body string) error {
+	path := fmt.Sprintf("/repos/%s/%s/issues/%d/comments", org, repo, pr)
+	if err := c.do(http.MethodPost, path, map[string]string{"body": body}, nil); err != nil {
+		return fmt.Errorf("commenting on #%d: %w", pr, err)
+	}
+	return nil
+}
+
+func (c *AppClient) ClosePR(org, repo string, pr int) error {
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", org, repo, pr)
+	if err := c.do(http.MethodPatch, path, map[string]string{"state": "closed"}, nil); err != nil {
+		return fmt.Errorf("closing #%d: %w", pr, err)
+	}
+	return nil
+}
+
+func (c *AppClient) GetPullRequest(org, repo string, pr int) (PullRequest, error) {
+	prInfo, err := c.getPullRequest(org, repo, pr)
+	if err != nil {
+		return PullRequest{}, err
+	}
+	return PullRequest{
+		Number:    prInfo.Number,
+		CreatedAt: prInfo.CreatedAt,
+		UpdatedAt: prInfo.UpdatedAt,
+	}, nil
+}
+
+func (c *AppClient) ListOpenRebasePRs(org, repo, headPrefix string) ([]PullRequest, error) {
+	var resp []appPullRequest
+	path := fmt.Sprintf("/repos/%s/%s/pulls?state=open", org, repo)
+	if err := c.do(http.MethodGet, path, nil, &resp); err != nil {
+		return nil, fmt.Errorf("listing pull requests for %s/%s: %w", org, repo, err)
+	}
+
+	var out []PullRequest
+	for _, pr := range resp {
+		if !strings.HasPrefix(pr.Head.Ref, headPrefix) {
+			continue
+		}
+		out = append(out, PullRequest{Number: pr.Number, CreatedAt: pr.CreatedAt, UpdatedAt: pr.UpdatedAt})
+	}
+	return out, nil
+}
+
+// appPullRequest is the subset of the GitHub REST API's pull request
+// object AppClient needs.
+type appPullRequest struct {
+	Number    int       `json:"number"`
+	Mergeable *bool     `json:"mergeable"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Head      struct {
+		SHA string `json:"sha"`
+		Ref string `json:"ref"`
+	} `json:"head"`
+}
+
+func (c *AppClient) getPullRequest(org, repo string, pr int) (appPullRequest, error) {
+	var out appPullRequest
+	path := fmt.Sprintf("/repos/%s/%s/pulls/%d", org, repo, pr)
+	if err := c.do(http.MethodGet, path, nil, &out); err != nil {
+		return appPullRequest{}, fmt.Errorf("getting PR #%d: %w", pr, err)
+	}
+	return out, nil
+}
+
+// do issues an authenticated request against the GitHub REST API,
+// refreshing the installation access token first if it's missing or
+// close to expiry.
+func (c *AppClient) do(method, path string, body, out interface{}) error {
+	token, err := c.token()
+	if err != nil {
+		return fmt.Errorf("getting installation token: %w", err)
+	}
+
+	var reqBody io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encoding request body: %w", err)
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequest(method, c.baseURL+path, reqBody)
+	if err != nil {
+		return fmt.Errorf("building request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s %s: %w", method, path, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("%s %s: unexpected status %s: %s", method, path, resp.Status, respBody)
+	}
+
+	if out == nil {
+		return nil
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// token returns a cached installation access token, minting a new one
+// via a freshly signed App JWT if the cached one is missing or about to
+// expire.
+func (c *AppClient) token() (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.installToken != "" && time.Until(c.installTokenExp) > time.Minute {
+		return c.installToken, nil
+	}
+
+	jwt, err := c.signAppJWT()
+	if err != nil {
+		return "", fmt.Errorf("signing App JWT: %w", err)
+	}
+
+	req, err := http.NewRequest(http.MethodPost,
+		fmt.Sprintf("%s/app/installations/%d/access_tokens", c.baseURL, c.installationID), nil)
+	if err != nil {
+		return "", fmt.Errorf("building installation token request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+jwt)
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("requesting installation token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(resp.Body)
+		return "", fmt.Errorf("unexpected status %s minting installation token: %s", resp.Status, respBody)
+	}
+
+	var tokenResp struct {
+		Token     string    `json:"token"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&tokenResp); err != nil {
+		return "", fmt.Errorf("decoding installation token response: %w", err)
+	}
+
+	c.installToken = tokenResp.Token
+	c.installTokenExp = tokenResp.ExpiresAt
+	return c.installToken, nil
+}
+
+// signAppJWT builds and RS256-signs a short-lived JWT identifying the
+// App itself, as required to mint an installation access token.
+func (c *AppClient) signAppJWT() (string, error) {
+	now := time.Now()
+	header := map[string]string{"alg": "RS256", "typ": "JWT"}
+	claims := map[string]interface{}{
+		"iat": now.Add(-time.Minute).Unix(),
+		"exp": now.Add(9 * time.Minute).Unix(),
+		"iss": c.appID,
+	}
+
+	headerJSON, err := json.Marshal(header)
+	if err != nil {
+		return "", err
+	}
+	claimsJSON, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+
+	signingInput := base64URLEncode(headerJSON) + "." + base64URLEncode(claimsJSON)
+
+	hashed := sha256.Sum256([]byte(signingInput))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, c.privateKey, crypto.SHA256, hashed[:])
+	if err != nil {
+		return "", fmt.Errorf("signing JWT: %w", err)
+	}
+
+	return signingInput + "." + base64URLEncode(signature), nil
+}
+
+func base64URLEncode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
@@ -0,0 +1,160 @@
+package github
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testAppClient(t *testing.T) (*AppClient, *rsa.PrivateKey) {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("generating test key: %v", err)
+	}
+	keyPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+
+	client, err := NewAppClient(123, 456, keyPEM)
+	if err != nil {
+		t.Fatalf("NewAppClient() error: %v", err)
+	}
+	return client, key
+}
+
+func TestAppClientSignAppJWT(t *testing.T) {
+	client, key := testAppClient(t)
+
+	jwt, err := client.signAppJWT()
+	if err != nil {
+		t.Fatalf("signAppJWT() error: %v", err)
+	}
+
+	parts := strings.Split(jwt, ".")
+	if len(parts) != 3 {
+		t.Fatalf("signAppJWT() = %q, want 3 dot-separated parts", jwt)
+	}
+
+	headerJSON, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		t.Fatalf("decoding header: %v", err)
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Typ string `json:"typ"`
+	}
+	if err := json.Unmarshal(headerJSON, &header); err != nil {
+		t.Fatalf("unmarshaling header: %v", err)
+	}
+	if header.Alg != "RS256" || header.Typ != "JWT" {
+		t.Errorf("header = %+v, want alg=RS256 typ=JWT", header)
+	}
+
+	claimsJSON, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		t.Fatalf("decoding claims: %v", err)
+	}
+	var claims struct {
+		Iss int64 `json:"iss"`
+		Iat int64 `json:"iat"`
+		Exp int64 `json:"exp"`
+	}
+	if err := json.Unmarshal(claimsJSON, &claims); err != nil {
+		t.Fatalf("unmarshaling claims: %v", err)
+	}
+	if claims.Iss != 123 {
+		t.Errorf("claims.Iss = %d, want 123", claims.Iss)
+	}
+	if claims.Exp <= claims.Iat {
+		t.Errorf("claims.Exp (%d) should be after claims.Iat (%d)", claims.Exp, claims.Iat)
+	}
+
+	signingInput := parts[0] + "." + parts[1]
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatalf("decoding signature: %v", err)
+	}
+	hashed := sha256.Sum256([]byte(signingInput))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		t.Errorf("signature does not verify against the client's public key: %v", err)
+	}
+}
+
+func TestAppClientToken(t *testing.T) {
+	client, _ := testAppClient(t)
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Method != http.MethodPost || r.URL.Path != "/app/installations/456/access_tokens" {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+		if auth := r.Header.Get("Authorization"); !strings.HasPrefix(auth, "Bearer ") {
+			t.Errorf("Authorization header = %q, want a Bearer JWT", auth)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"token":      "installation-token",
+			"expires_at": time.Now().Add(time.Hour).Format(time.RFC3339),
+		})
+	}))
+	defer srv.Close()
+	client.baseURL = srv.URL
+
+	token, err := client.token()
+	if err != nil {
+		t.Fatalf("token() error: %v", err)
+	}
+	if token != "installation-token" {
+		t.Errorf("token() = %q, want %q", token, "installation-token")
+	}
+
+	// A second call within the token's lifetime must be served from
+	// cache rather than minting a new installation token.
+	if _, err := client.token(); err != nil {
+		t.Fatalf("token() error: %v", err)
+	}
+	if requests != 1 {
+		t.Errorf("server received %d requests, want 1 (second token() should hit the cache)", requests)
+	}
+}
+
+func TestAppClientTokenRefreshesWhenExpired(t *testing.T) {
+	client, _ := testAppClient(t)
+
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]string{
+			"token": "installation-token",
+			// Already expired, so the next call must refresh.
+			"expires_at": time.Now().Add(-time.Minute).Format(time.RFC3339),
+		})
+	}))
+	defer srv.Close()
+	client.baseURL = srv.URL
+
+	if _, err := client.token(); err != nil {
+		t.Fatalf("token() error: %v", err)
+	}
+	if _, err := client.token(); err != nil {
+		t.Fatalf("token() error: %v", err)
+	}
+	if requests != 2 {
+		t.Errorf("server received %d requests, want 2 (expired token should be refreshed)", requests)
+	}
+}
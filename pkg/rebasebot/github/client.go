@@ -0,0 +1,47 @@
+// Package github provides a narrow interface over the GitHub APIs
+// rebasebot's automerge subsystem needs (statuses, mergeability, labels,
+// comments), so the same polling logic serves repos gated by Prow tide
+// and repos gated by openshift-merge-bot's GitHub-App flow alike.
+package github
+
+import "time"
+
+// Client is the subset of the GitHub API rebasebot's automerge
+// subsystem needs.
+type Client interface {
+	// ListStatuses returns the status contexts (e.g. Prow jobs) reported
+	// against a PR's head commit through GitHub's commit status API.
+	// Check runs reported through the checks API, such as GitHub
+	// Actions jobs, are not included.
+	ListStatuses(org, repo string, pr int) ([]Status, error)
+
+	// IsMergeable reports whether the PR currently has no merge
+	// conflicts against its base branch.
+	IsMergeable(org, repo string, pr int) (bool, error)
+
+	AddLabels(org, repo string, pr int, labels ...string) error
+	RemoveLabels(org, repo string, pr int, labels ...string) error
+	CreateComment(org, repo string, pr int, body string) error
+	ClosePR(org, repo string, pr int) error
+
+	// GetPullRequest returns a single PR's metadata, used to compute
+	// how long a rebase PR took to merge.
+	GetPullRequest(org, repo string, pr int) (PullRequest, error)
+
+	// ListOpenRebasePRs returns open PRs whose head branch starts with
+	// headPrefix, for the stale-PR closer to consider.
+	ListOpenRebasePRs(org, repo, headPrefix string) ([]PullRequest, error)
+}
+
+// Status is a single status context as reported by ListStatuses.
+type Status struct {
+	Context string
+	State   string // "success", "pending", "failure", or "error"
+}
+
+// PullRequest is the minimal PR metadata the automerge subsystem needs.
+type PullRequest struct {
+	Number    int
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
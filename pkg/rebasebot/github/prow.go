@@ -0,0 +1,113 @@
+package github
+
+import (
+	"fmt"
+	"strings"
+
+	prowgh "k8s.io/test-infra/prow/github"
+)
+
+// ProwClient implements Client on top of k8s.io/test-infra/prow/github,
+// for repos gated by Prow tide.
+type ProwClient struct {
+	delegate prowgh.Client
+}
+
+// NewProwClient builds a ProwClient authenticating as a Prow bot with a
+// static token.
+func NewProwClient(token string) *ProwClient {
+	return &ProwClient{
+		delegate: prowgh.NewClient(
+			func() []byte { return []byte(token) },
+			nil,
+			prowgh.DefaultGraphQLEndpoint,
+			prowgh.DefaultAPIEndpoint,
+		),
+	}
+}
+
+func (c *ProwClient) ListStatuses(org, repo string, pr int) ([]Status, error) {
+	prInfo, err := c.delegate.GetPullRequest(org, repo, pr)
+	if err != nil {
+		return nil, fmt.Errorf("getting PR #%d: %w", pr, err)
+	}
+
+	statuses, err := c.delegate.ListStatuses(org, repo, prInfo.Head.SHA)
+	if err != nil {
+		return nil, fmt.Errorf("listing statuses for %s: %w", prInfo.Head.SHA, err)
+	}
+
+	out := make([]Status, 0, len(statuses))
+	for _, s := range statuses {
+		out = append(out, Status{Context: s.Context, State: s.State})
+	}
+	return out, nil
+}
+
+func (c *ProwClient) IsMergeable(org, repo string, pr int) (bool, error) {
+	prInfo, err := c.delegate.GetPullRequest(org, repo, pr)
+	if err != nil {
+		return false, fmt.Errorf("getting PR #%d: %w", pr, err)
+	}
+	return prInfo.Mergable == nil || *prInfo.Mergable, nil
+}
+
+func (c *ProwClient) AddLabels(org, repo string, pr int, labels ...string) error {
+	for _, label := range labels {
+		if err := c.delegate.AddLabel(org, repo, pr, label); err != nil {
+			return fmt.Errorf("adding label %q to #%d: %w", label, pr, err)
+		}
+	}
+	return nil
+}
+
+func (c *ProwClient) RemoveLabels(org, repo string, pr int, labels ...string) error {
+	for _, label := range labels {
+		if err := c.delegate.RemoveLabel(org, repo, pr, label); err != nil {
+			return fmt.Errorf("removing label %q from #%d: %w", label, pr, err)
+		}
+	}
+	return nil
+}
+
+func (c *ProwClient) CreateComment(org, repo string, pr int, body string) error {
+	if err := c.delegate.CreateComment(org, repo, pr, body); err != nil {
+		return fmt.Errorf("commenting on #%d: %w", pr, err)
+	}
+	return nil
+}
+
+func (c *ProwClient) ClosePR(org, repo string, pr int) error {
+	if err := c.delegate.ClosePR(org, repo, pr); err != nil {
+		return fmt.Errorf("closing #%d: %w", pr, err)
+	}
+	return nil
+}
+
+func (c *ProwClient) GetPullRequest(org, repo string, pr int) (PullRequest, error) {
+	prInfo, err := c.delegate.GetPullRequest(org, repo, pr)
+	if err != nil {
+		return PullRequest{}, fmt.Errorf("getting PR #%d: %w", pr, err)
+	}
+	return PullRequest{
+		Number:    prInfo.Number,
+		CreatedAt: prInfo.CreatedAt,
+		UpdatedAt: prInfo.UpdatedAt,
+	}, nil
+}
+
+func (c *ProwClient) ListOpenRebasePRs(org, repo, headPrefix string) ([]PullRequest, error) {
+	prs, err := c.delegate.GetPullRequests(org, repo)
+	if err != nil {
+		return nil, fmt.Errorf("listing pull requests for %s/%s: %w", org, repo, err)
+	}
+
+	var out []PullRequest
+	for _, pr := range prs {
+		if pr.State != "open" || !strings.HasPrefix(pr.Head.Ref, headPrefix) {
+			continue
+		}
+		out = append(out, PullRequest{Number: pr.Number, CreatedAt: pr.CreatedAt, UpdatedAt: pr.UpdatedAt})
+	}
+	return out, nil
+}
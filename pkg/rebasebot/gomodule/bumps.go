@@ -0,0 +1,38 @@
+package gomodule
+
+import (
+	"fmt"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Summarize compares the require blocks of two go.mod revisions and
+// describes every module whose pinned version changed or was added, for
+// inclusion in the rebase PR description.
+func Summarize(path string, before, after []byte) ([]string, error) {
+	beforeFile, err := modfile.Parse(path, before, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing previous %s: %w", path, err)
+	}
+	afterFile, err := modfile.Parse(path, after, nil)
+	if err != nil {
+		return nil, fmt.Errorf("parsing merged %s: %w", path, err)
+	}
+
+	prev := make(map[string]string, len(beforeFile.Require))
+	for _, req := range beforeFile.Require {
+		prev[req.Mod.Path] = req.Mod.Version
+	}
+
+	var bumps []string
+	for _, req := range afterFile.Require {
+		old, existed := prev[req.Mod.Path]
+		switch {
+		case !existed:
+			bumps = append(bumps, fmt.Sprintf("%s: added at %s", req.Mod.Path, req.Mod.Version))
+		case old != req.Mod.Version:
+			bumps = append(bumps, fmt.Sprintf("%s: %s -> %s", req.Mod.Path, old, req.Mod.Version))
+		}
+	}
+	return bumps, nil
+}
@@ -0,0 +1,64 @@
+package gomodule
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// Order returns modules sorted so that a module appears after every
+// other module it locally replaces, so tidy/build can run module by
+// module without hitting unresolved local dependencies. It returns an
+// error if the local replace graph has a cycle.
+func Order(modules []Module) ([]Module, error) {
+	byDir := make(map[string]Module, len(modules))
+	for _, m := range modules {
+		byDir[m.Dir] = m
+	}
+
+	// Resolve each module's local replaces to the Dir of the module
+	// they point at, so the DAG is keyed consistently regardless of
+	// which module the replace was declared in.
+	deps := make(map[string][]string, len(modules))
+	for _, m := range modules {
+		for _, rel := range m.LocalReplaces {
+			target := filepath.Clean(filepath.Join(m.Dir, rel))
+			if _, ok := byDir[target]; ok {
+				deps[m.Dir] = append(deps[m.Dir], target)
+			}
+		}
+	}
+
+	const (
+		unvisited = 0
+		visiting  = 1
+		done      = 2
+	)
+	state := make(map[string]int, len(modules))
+	var ordered []Module
+
+	var visit func(dir string) error
+	visit = func(dir string) error {
+		switch state[dir] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("cyclic local replace involving module %q", dir)
+		}
+		state[dir] = visiting
+		for _, dep := range deps[dir] {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[dir] = done
+		ordered = append(ordered, byDir[dir])
+		return nil
+	}
+
+	for _, m := range modules {
+		if err := visit(m.Dir); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
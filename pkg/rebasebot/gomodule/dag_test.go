@@ -0,0 +1,79 @@
+package gomodule
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrder(t *testing.T) {
+	tests := []struct {
+		name    string
+		modules []Module
+		want    []string // Dirs, in expected order
+		wantErr bool
+	}{
+		{
+			name: "no dependencies",
+			modules: []Module{
+				{Dir: "."},
+				{Dir: "tools"},
+			},
+			want: []string{".", "tools"},
+		},
+		{
+			name: "dependency ordered after its replace target",
+			modules: []Module{
+				{Dir: ".", LocalReplaces: []string{"api"}},
+				{Dir: "api"},
+			},
+			want: []string{"api", "."},
+		},
+		{
+			name: "transitive chain",
+			modules: []Module{
+				{Dir: ".", LocalReplaces: []string{"tools"}},
+				{Dir: "tools", LocalReplaces: []string{"../api"}},
+				{Dir: "api"},
+			},
+			want: []string{"api", "tools", "."},
+		},
+		{
+			name: "replace target outside the module set is ignored",
+			modules: []Module{
+				{Dir: ".", LocalReplaces: []string{"../not-in-repo"}},
+			},
+			want: []string{"."},
+		},
+		{
+			name: "cycle is rejected",
+			modules: []Module{
+				{Dir: "a", LocalReplaces: []string{"../b"}},
+				{Dir: "b", LocalReplaces: []string{"../a"}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Order(tt.modules)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Order() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Order() unexpected error: %v", err)
+			}
+
+			var gotDirs []string
+			for _, m := range got {
+				gotDirs = append(gotDirs, m.Dir)
+			}
+			if !reflect.DeepEqual(gotDirs, tt.want) {
+				t.Errorf("Order() dirs = %v, want %v", gotDirs, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,120 @@
+// Package gomodule discovers the go.mod files inside a multi-module
+// worktree, orders them by their local replace dependencies, and
+// summarizes the require bumps a rebase produced in each of them.
+package gomodule
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/mod/modfile"
+)
+
+// Module represents a single go.mod file discovered inside a worktree.
+type Module struct {
+	// Dir is the module's directory, relative to the repo root ("." for
+	// the root module).
+	Dir string
+	// Path is the module path declared in go.mod.
+	Path string
+	// LocalReplaces lists the filesystem paths this module replaces
+	// other modules with, e.g. "../api" from a
+	// `replace example.com/foo/api => ../api` directive, relative to
+	// Dir. They are the edges of the module dependency DAG.
+	LocalReplaces []string
+}
+
+// Discover returns every go.mod file found at ref, in no particular
+// order. It reads ref's git tree rather than the live worktree, since
+// Discover is called while resolving merge conflicts and the worktree's
+// go.mod files may still contain conflict markers at that point. A
+// directory is skipped if it matches denylist; when allowlist is
+// non-empty, only directories matching it are kept. Patterns are
+// filepath.Match patterns evaluated against the repo-relative module
+// directory (e.g. "vendor/*"), so monorepos can skip vendored trees.
+func Discover(repoDir, ref string, allowlist, denylist []string) ([]Module, error) {
+	paths, err := gitListTree(repoDir, ref)
+	if err != nil {
+		return nil, fmt.Errorf("listing tree at %s: %w", ref, err)
+	}
+
+	var modules []Module
+	for _, path := range paths {
+		if filepath.Base(path) != "go.mod" {
+			continue
+		}
+
+		relDir := filepath.Dir(path)
+		if !allowed(relDir, allowlist, denylist) {
+			continue
+		}
+
+		content, err := gitShow(repoDir, ref, path)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s at %s: %w", path, ref, err)
+		}
+		f, err := modfile.Parse(path, content, nil)
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s at %s: %w", path, ref, err)
+		}
+
+		mod := Module{Dir: relDir, Path: f.Module.Mod.Path}
+		for _, rep := range f.Replace {
+			if isLocal(rep.New.Path) {
+				mod.LocalReplaces = append(mod.LocalReplaces, rep.New.Path)
+			}
+		}
+		modules = append(modules, mod)
+	}
+	return modules, nil
+}
+
+// gitListTree returns every file path tracked at ref.
+func gitListTree(repoDir, ref string) ([]string, error) {
+	cmd := exec.Command("git", "ls-tree", "-r", "--name-only", ref)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, err
+	}
+	trimmed := strings.TrimSpace(string(out))
+	if trimmed == "" {
+		return nil, nil
+	}
+	return strings.Split(trimmed, "\n"), nil
+}
+
+// gitShow returns the raw contents of path as it exists at ref.
+func gitShow(repoDir, ref, path string) ([]byte, error) {
+	cmd := exec.Command("git", "show", ref+":"+path)
+	cmd.Dir = repoDir
+	return cmd.Output()
+}
+
+// isLocal reports whether a replace target is a filesystem path (as
+// opposed to a module path + version).
+func isLocal(replacePath string) bool {
+	if replacePath == "" {
+		return false
+	}
+	return filepath.IsAbs(replacePath) || replacePath[0] == '.'
+}
+
+func allowed(dir string, allowlist, denylist []string) bool {
+	for _, pattern := range denylist {
+		if matched, _ := filepath.Match(pattern, dir); matched {
+			return false
+		}
+	}
+	if len(allowlist) == 0 {
+		return true
+	}
+	for _, pattern := range allowlist {
+		if matched, _ := filepath.Match(pattern, dir); matched {
+			return true
+		}
+	}
+	return false
+}
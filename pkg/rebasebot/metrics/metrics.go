@@ -0,0 +1,50 @@
+// Package metrics defines the Prometheus metrics rebasebot exposes on
+// its /metrics endpoint, registered against controller-runtime's
+// metrics registry so they're served by the same manager that runs the
+// automerge polling loop.
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// RebasePRsMerged counts rebase PRs the automerge subsystem handed
+	// off for merge.
+	RebasePRsMerged = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "rebasebot_rebase_prs_merged_total",
+		Help: "Number of rebase pull requests handed off for merge.",
+	})
+
+	// StalePRsClosed counts abandoned rebase PRs the stale-PR closer
+	// has closed.
+	StalePRsClosed = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "rebasebot_stale_prs_closed_total",
+		Help: "Number of abandoned rebase pull requests closed by the stale-PR closer.",
+	})
+
+	// TimeToMergeSeconds observes the time between a rebase PR being
+	// opened and being handed off for merge.
+	TimeToMergeSeconds = prometheus.NewHistogram(prometheus.HistogramOpts{
+		Name:    "rebasebot_time_to_merge_seconds",
+		Help:    "Time between a rebase PR being opened and being handed off for merge.",
+		Buckets: prometheus.ExponentialBuckets(60, 2, 12), // 1m .. ~34h
+	})
+
+	// ConflictClassesHit counts, per conflict.Resolver class, how often
+	// that class of conflict was encountered during a rebase.
+	ConflictClassesHit = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "rebasebot_conflict_classes_hit_total",
+		Help: "Number of times each conflict class was encountered during a rebase.",
+	}, []string{"class"})
+)
+
+func init() {
+	ctrlmetrics.Registry.MustRegister(
+		RebasePRsMerged,
+		StalePRsClosed,
+		TimeToMergeSeconds,
+		ConflictClassesHit,
+	)
+}
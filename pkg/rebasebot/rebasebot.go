@@ -0,0 +1,275 @@
+// Package rebasebot implements the rebase engine: cloning the downstream
+// fork, merging an upstream tag on top of it, and resolving the merge
+// conflicts that commonly result from carrying downstream patches.
+package rebasebot
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/conflict"
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/gomodule"
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/metrics"
+	"github.com/shiftstack/merge-bot/pkg/rebasebot/toolchain"
+)
+
+// goModResolver is the single registered conflict.GoModResolver
+// instance. Run reconfigures it from Options before every rebase rather
+// than having each run build and register its own, since the conflict
+// registry is a process-wide extension point that third-party resolvers
+// (vendor/modules.txt, OWNERS, ...) also register themselves into.
+var goModResolver = &conflict.GoModResolver{}
+
+func init() {
+	conflict.Register(goModResolver)
+}
+
+// Options configures a single rebase run: which downstream branch to
+// rebase, which upstream tag to rebase onto, and where to push the
+// result.
+type Options struct {
+	// WorkDir is a scratch directory rebasebot clones the downstream
+	// repo into. It is not removed afterwards so a failed run can be
+	// inspected.
+	WorkDir string
+
+	SourceRemote string // e.g. "origin", pointing at the downstream fork
+	SourceBranch string
+
+	UpstreamRemote string // e.g. "upstream"
+	UpstreamTag    string
+
+	// ModuleAllowlist and ModuleDenylist restrict which go.mod-rooted
+	// modules rebasebot discovers in a multi-module repo. Patterns are
+	// filepath.Match patterns evaluated against a module's repo-relative
+	// directory (e.g. "vendor/*"). An empty ModuleAllowlist matches
+	// every module not excluded by ModuleDenylist.
+	ModuleAllowlist []string
+	ModuleDenylist  []string
+
+	// ToolchainPolicy decides which `go` directive version wins when
+	// upstream and downstream disagree: "prefer-upstream" (default),
+	// "prefer-downstream", "max", or "pinned:<version>".
+	ToolchainPolicy string
+
+	// ToolchainCacheDir is an optional directory of pre-installed Go
+	// toolchains consulted before falling back to a `toolchain`
+	// directive when the chosen version isn't the one on $PATH.
+	ToolchainCacheDir string
+
+	// DryRun stops the run right before pushing the rebased branch.
+	DryRun bool
+}
+
+// Result summarizes what a rebase run did, for logging and for the PR
+// description.
+type Result struct {
+	// UnresolvedConflicts holds one entry per semantic conflict a
+	// resolver detected but could not safely reconcile on its own.
+	UnresolvedConflicts []string
+
+	// ModuleBumps maps a module's repo-relative directory to the
+	// dependency bumps the rebase applied to its go.mod, for the PR
+	// description to enumerate per module.
+	ModuleBumps map[string][]string
+
+	// DockerfilePatches lists the Dockerfiles whose golang base image
+	// was rewritten to match the root module's reconciled Go version.
+	DockerfilePatches []string
+}
+
+// Run clones the downstream branch, merges the upstream tag on top of
+// it, resolves any conflicts rebasebot knows how to handle, and (unless
+// DryRun is set) pushes the result.
+func Run(log *logrus.Entry, opts Options) (*Result, error) {
+	if err := clone(opts.WorkDir, opts.SourceRemote, opts.SourceBranch); err != nil {
+		return nil, fmt.Errorf("cloning %s: %w", opts.SourceBranch, err)
+	}
+
+	if err := fetchTag(opts.WorkDir, opts.UpstreamRemote, opts.UpstreamTag); err != nil {
+		return nil, fmt.Errorf("fetching upstream tag %s: %w", opts.UpstreamTag, err)
+	}
+
+	policy, err := toolchain.ParsePolicy(opts.ToolchainPolicy)
+	if err != nil {
+		return nil, fmt.Errorf("parsing toolchain policy: %w", err)
+	}
+	goModResolver.ToolchainPolicy = policy
+	goModResolver.ToolchainCacheDir = opts.ToolchainCacheDir
+
+	if err := merge(opts.WorkDir, "FETCH_HEAD"); err != nil {
+		log.WithError(err).Info("default merge hit conflicts, attempting automatic resolution")
+	}
+
+	unresolved, moduleBumps, rootGoVersion, err := resolveConflicts(opts.WorkDir, opts.ModuleAllowlist, opts.ModuleDenylist)
+	if err != nil {
+		return nil, err
+	}
+
+	if remaining, err := conflictedFiles(opts.WorkDir); err != nil {
+		return nil, fmt.Errorf("listing remaining conflicts: %w", err)
+	} else if len(remaining) > 0 {
+		return nil, fmt.Errorf("unresolved conflicts in: %v", remaining)
+	}
+
+	var dockerfilePatches []string
+	if rootGoVersion != "" {
+		dockerfilePatches, err = patchDockerfiles(opts.WorkDir, rootGoVersion)
+		if err != nil {
+			return nil, fmt.Errorf("patching Dockerfile golang base images: %w", err)
+		}
+		for _, f := range dockerfilePatches {
+			if err := add(opts.WorkDir, f); err != nil {
+				return nil, fmt.Errorf("staging patched %s: %w", f, err)
+			}
+		}
+	}
+
+	if err := commit(opts.WorkDir, fmt.Sprintf("Rebase onto %s", opts.UpstreamTag)); err != nil {
+		return nil, fmt.Errorf("committing merge: %w", err)
+	}
+
+	if !opts.DryRun {
+		if err := push(opts.WorkDir, opts.SourceRemote, opts.SourceBranch); err != nil {
+			return nil, fmt.Errorf("pushing %s: %w", opts.SourceBranch, err)
+		}
+	}
+
+	return &Result{
+		UnresolvedConflicts: unresolved,
+		ModuleBumps:         moduleBumps,
+		DockerfilePatches:   dockerfilePatches,
+	}, nil
+}
+
+// resolveConflicts discovers every go.mod-rooted module as it existed
+// at HEAD (the pre-merge downstream branch, since the worktree's copy
+// may still have conflict markers in it at this point), runs the
+// registered conflict.Resolvers over every conflicted path left by the
+// default 3-way merge in topological module order (so a module is
+// never tidied before the local modules it replaces), and stages
+// whatever they manage to fix. Paths with no matching resolver, and
+// go.mod files outside the allow/denylist, are left conflicted for
+// Run's caller to report. It also returns the `go` directive version
+// the root module settled on, if any, so Dockerfile base images can be
+// kept in step with it.
+func resolveConflicts(repoDir string, allowlist, denylist []string) (unresolved []string, moduleBumps map[string][]string, rootGoVersion string, err error) {
+	modules, err := gomodule.Discover(repoDir, "HEAD", allowlist, denylist)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("discovering go modules: %w", err)
+	}
+	modules, err = gomodule.Order(modules)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("ordering go modules: %w", err)
+	}
+
+	moduleOrder := make(map[string]int, len(modules))
+	allowedDirs := make(map[string]bool, len(modules))
+	for i, m := range modules {
+		moduleOrder[m.Dir] = i
+		allowedDirs[m.Dir] = true
+	}
+
+	conflicted, err := conflictedFiles(repoDir)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("listing conflicted files: %w", err)
+	}
+	sort.SliceStable(conflicted, func(i, j int) bool {
+		return moduleOrder[filepath.Dir(conflicted[i])] < moduleOrder[filepath.Dir(conflicted[j])]
+	})
+
+	moduleBumps = map[string][]string{}
+	for _, path := range conflicted {
+		dir := filepath.Dir(path)
+		isGoMod := filepath.Base(path) == "go.mod"
+		if isGoMod && !allowedDirs[dir] {
+			continue
+		}
+
+		resolver := conflict.For(path)
+		if resolver == nil {
+			continue
+		}
+
+		var before []byte
+		if isGoMod {
+			before, _ = gitShowBytes(repoDir, "HEAD", path)
+		}
+
+		res, resolveErr := resolver.Resolve(repoDir, path, "HEAD", "FETCH_HEAD")
+		if resolveErr != nil {
+			return nil, nil, "", fmt.Errorf("resolving %s: %w", path, resolveErr)
+		}
+		metrics.ConflictClassesHit.WithLabelValues(filepath.Base(path)).Inc()
+		if res.Unresolved != "" {
+			unresolved = append(unresolved, fmt.Sprintf("%s: %s", path, res.Unresolved))
+		}
+		if !res.Resolved {
+			continue
+		}
+		if err := add(repoDir, path); err != nil {
+			return nil, nil, "", fmt.Errorf("staging resolved %s: %w", path, err)
+		}
+		for _, extra := range res.ExtraResolved {
+			if _, err := os.Stat(filepath.Join(repoDir, extra)); err != nil {
+				continue
+			}
+			if err := add(repoDir, extra); err != nil {
+				return nil, nil, "", fmt.Errorf("staging resolved %s: %w", extra, err)
+			}
+		}
+		if isGoMod && dir == "." && res.GoVersion != "" {
+			rootGoVersion = res.GoVersion
+		}
+
+		if isGoMod && before != nil {
+			if after, err := os.ReadFile(filepath.Join(repoDir, path)); err == nil {
+				if bumps, err := gomodule.Summarize(path, before, after); err == nil && len(bumps) > 0 {
+					moduleBumps[dir] = bumps
+				}
+			}
+		}
+	}
+	return unresolved, moduleBumps, rootGoVersion, nil
+}
+
+// patchDockerfiles rewrites the golang base image version in every
+// Dockerfile (Dockerfile, Dockerfile.rhel, ...) under repoDir to
+// version, returning the repo-relative paths it changed.
+func patchDockerfiles(repoDir, version string) ([]string, error) {
+	var patched []string
+	err := filepath.WalkDir(repoDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasPrefix(d.Name(), "Dockerfile") {
+			return nil
+		}
+
+		count, err := toolchain.PatchDockerfile(path, version)
+		if err != nil {
+			return err
+		}
+		if count > 0 {
+			rel, err := filepath.Rel(repoDir, path)
+			if err != nil {
+				return err
+			}
+			patched = append(patched, rel)
+		}
+		return nil
+	})
+	return patched, err
+}
@@ -0,0 +1,41 @@
+package toolchain
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+)
+
+// dockerfileGoImage matches a FROM line pinning a golang base image,
+// e.g. `FROM registry.ci.openshift.org/openshift/release:golang-1.20` or
+// `FROM golang:1.20-bullseye`, capturing the version so it can be
+// rewritten in place.
+var dockerfileGoImage = regexp.MustCompile(`(?m)^(FROM\s+\S*golang[:-])(\d+\.\d+(?:\.\d+)?)(\S*)`)
+
+// PatchDockerfile rewrites every golang base-image version reference in
+// path to version, returning the number of references it changed. It is
+// a no-op, returning 0, if path has no such reference.
+func PatchDockerfile(path, version string) (int, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("reading %s: %w", path, err)
+	}
+
+	count := 0
+	patched := dockerfileGoImage.ReplaceAllFunc(content, func(match []byte) []byte {
+		replaced := dockerfileGoImage.ReplaceAll(match, []byte(`${1}`+version+`${3}`))
+		if !bytes.Equal(replaced, match) {
+			count++
+		}
+		return replaced
+	})
+	if count == 0 {
+		return 0, nil
+	}
+
+	if err := os.WriteFile(path, patched, 0o644); err != nil {
+		return 0, fmt.Errorf("writing %s: %w", path, err)
+	}
+	return count, nil
+}
@@ -0,0 +1,85 @@
+// Package toolchain reconciles the Go version a rebased go.mod should
+// declare when upstream and downstream disagree on the `go` directive,
+// and keeps Dockerfile base images in step with whatever version wins.
+package toolchain
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/mod/semver"
+)
+
+// mode selects how Policy.Resolve picks between two `go` directive
+// versions.
+type mode int
+
+const (
+	// PreferUpstream always takes the upstream version. This is the
+	// zero value, since it matches the behavior of a plain 3-way merge
+	// that simply takes upstream's go.mod changes.
+	PreferUpstream mode = iota
+	PreferDownstream
+	Max
+	Pinned
+)
+
+// Policy decides which Go toolchain version a rebased go.mod should
+// declare.
+type Policy struct {
+	mode   mode
+	pinned string
+}
+
+// ParsePolicy parses a policy string as accepted by rebasebot's
+// -toolchain-policy flag: "prefer-upstream", "prefer-downstream", "max",
+// or "pinned:<version>". An empty string means PreferUpstream.
+func ParsePolicy(s string) (Policy, error) {
+	switch {
+	case s == "" || s == "prefer-upstream":
+		return Policy{mode: PreferUpstream}, nil
+	case s == "prefer-downstream":
+		return Policy{mode: PreferDownstream}, nil
+	case s == "max":
+		return Policy{mode: Max}, nil
+	case strings.HasPrefix(s, "pinned:"):
+		version := strings.TrimPrefix(s, "pinned:")
+		if version == "" {
+			return Policy{}, fmt.Errorf("pinned toolchain policy requires a version, e.g. pinned:1.21.0")
+		}
+		return Policy{mode: Pinned, pinned: version}, nil
+	default:
+		return Policy{}, fmt.Errorf("unknown toolchain policy %q", s)
+	}
+}
+
+// Resolve picks the `go` directive version to use given the downstream
+// (ours) and upstream (theirs) versions. Either side may be empty if
+// that go.mod predates the `go` directive.
+func (p Policy) Resolve(ours, theirs string) string {
+	switch p.mode {
+	case PreferDownstream:
+		if ours != "" {
+			return ours
+		}
+		return theirs
+	case Max:
+		if ours == "" {
+			return theirs
+		}
+		if theirs == "" {
+			return ours
+		}
+		if semver.Compare("v"+theirs, "v"+ours) > 0 {
+			return theirs
+		}
+		return ours
+	case Pinned:
+		return p.pinned
+	default: // PreferUpstream
+		if theirs != "" {
+			return theirs
+		}
+		return ours
+	}
+}
@@ -0,0 +1,65 @@
+package toolchain
+
+import "testing"
+
+func TestParsePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		want    Policy
+		wantErr bool
+	}{
+		{name: "empty defaults to prefer-upstream", in: "", want: Policy{mode: PreferUpstream}},
+		{name: "prefer-upstream", in: "prefer-upstream", want: Policy{mode: PreferUpstream}},
+		{name: "prefer-downstream", in: "prefer-downstream", want: Policy{mode: PreferDownstream}},
+		{name: "max", in: "max", want: Policy{mode: Max}},
+		{name: "pinned", in: "pinned:1.21.0", want: Policy{mode: Pinned, pinned: "1.21.0"}},
+		{name: "pinned without version is an error", in: "pinned:", wantErr: true},
+		{name: "unknown policy is an error", in: "bogus", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParsePolicy(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParsePolicy(%q) = %v, want error", tt.in, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePolicy(%q) unexpected error: %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Errorf("ParsePolicy(%q) = %+v, want %+v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyResolve(t *testing.T) {
+	tests := []struct {
+		name         string
+		policy       Policy
+		ours, theirs string
+		want         string
+	}{
+		{name: "prefer-upstream picks theirs", policy: Policy{mode: PreferUpstream}, ours: "1.20", theirs: "1.21", want: "1.21"},
+		{name: "prefer-upstream falls back to ours when theirs is empty", policy: Policy{mode: PreferUpstream}, ours: "1.20", theirs: "", want: "1.20"},
+		{name: "prefer-downstream picks ours", policy: Policy{mode: PreferDownstream}, ours: "1.20", theirs: "1.21", want: "1.20"},
+		{name: "prefer-downstream falls back to theirs when ours is empty", policy: Policy{mode: PreferDownstream}, ours: "", theirs: "1.21", want: "1.21"},
+		{name: "max picks the higher version", policy: Policy{mode: Max}, ours: "1.21", theirs: "1.20", want: "1.21"},
+		{name: "max picks theirs when it's higher", policy: Policy{mode: Max}, ours: "1.20", theirs: "1.21", want: "1.21"},
+		{name: "max falls back to theirs when ours is empty", policy: Policy{mode: Max}, ours: "", theirs: "1.21", want: "1.21"},
+		{name: "max falls back to ours when theirs is empty", policy: Policy{mode: Max}, ours: "1.21", theirs: "", want: "1.21"},
+		{name: "pinned always returns the pinned version", policy: Policy{mode: Pinned, pinned: "1.22.0"}, ours: "1.20", theirs: "1.21", want: "1.22.0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.Resolve(tt.ours, tt.theirs); got != tt.want {
+				t.Errorf("Resolve(%q, %q) = %q, want %q", tt.ours, tt.theirs, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,40 @@
+package toolchain
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// Apply makes sure modPath's module is actually built with version,
+// returning the go binary the caller should invoke to do so. If a
+// matching Go binary is installed under cacheDir, its path is returned
+// so the caller actually selects that toolchain, rather than leaving
+// the `go` directive change to take effect on its own. Otherwise Apply
+// writes a `toolchain goVersion` directive so the go command downloads
+// a matching toolchain the next time it runs, and returns "" so the
+// caller keeps using whichever go binary it already had.
+func Apply(modPath, version, cacheDir string) (string, error) {
+	if cacheDir != "" {
+		goBin := filepath.Join(cacheDir, "go"+version, "bin", "go")
+		if _, err := exec.LookPath(goBin); err == nil {
+			return goBin, nil
+		}
+	}
+
+	if err := runGo(filepath.Dir(modPath), "mod", "edit", "-toolchain=go"+version); err != nil {
+		return "", fmt.Errorf("setting toolchain directive to go%s: %w", version, err)
+	}
+	return "", nil
+}
+
+func runGo(dir string, args ...string) error {
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("go %s: %w: %s", strings.Join(args, " "), err, out)
+	}
+	return nil
+}
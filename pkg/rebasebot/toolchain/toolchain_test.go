@@ -0,0 +1,96 @@
+package toolchain
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// newCacheDirWithGo creates a toolchain cache directory containing a
+// runnable stand-in "go" binary for the given version, mimicking a
+// pre-installed toolchain under ToolchainCacheDir.
+func newCacheDirWithGo(t *testing.T, version string) string {
+	t.Helper()
+	cacheDir := t.TempDir()
+	binDir := filepath.Join(cacheDir, "go"+version, "bin")
+	if err := os.MkdirAll(binDir, 0o755); err != nil {
+		t.Fatalf("mkdir %s: %v", binDir, err)
+	}
+
+	goBin := filepath.Join(binDir, "go")
+	if runtime.GOOS == "windows" {
+		goBin += ".exe"
+	}
+	if err := os.WriteFile(goBin, []byte("#!/bin/sh\nexit 0\n"), 0o755); err != nil {
+		t.Fatalf("writing stand-in go binary: %v", err)
+	}
+	return cacheDir
+}
+
+func TestApplyCacheHitReturnsCachedGoBinary(t *testing.T) {
+	cacheDir := newCacheDirWithGo(t, "1.99.0")
+	modPath := filepath.Join(t.TempDir(), "go.mod")
+	if err := os.WriteFile(modPath, []byte("module example.com/test\n\ngo 1.99.0\n"), 0o644); err != nil {
+		t.Fatalf("writing go.mod: %v", err)
+	}
+
+	goBin, err := Apply(modPath, "1.99.0", cacheDir)
+	if err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+
+	want := filepath.Join(cacheDir, "go1.99.0", "bin", "go")
+	if goBin != want {
+		t.Errorf("Apply() goBin = %q, want %q (the caller must actually select the cached toolchain)", goBin, want)
+	}
+
+	// A cache hit must not also write a toolchain directive: the go.mod
+	// content is untouched.
+	content, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatalf("reading go.mod: %v", err)
+	}
+	if string(content) != "module example.com/test\n\ngo 1.99.0\n" {
+		t.Errorf("go.mod was modified on a cache hit: %q", content)
+	}
+}
+
+func TestApplyCacheMissWritesToolchainDirective(t *testing.T) {
+	if _, err := os.Stat("/usr/bin/go"); err == nil {
+		// best effort: rely on whatever "go" is on $PATH for this test
+	}
+
+	dir := t.TempDir()
+	modPath := filepath.Join(dir, "go.mod")
+	if err := os.WriteFile(modPath, []byte("module example.com/test\n\ngo 1.20\n"), 0o644); err != nil {
+		t.Fatalf("writing go.mod: %v", err)
+	}
+
+	goBin, err := Apply(modPath, "1.20", "")
+	if err != nil {
+		t.Fatalf("Apply() error: %v", err)
+	}
+	if goBin != "" {
+		t.Errorf("Apply() goBin = %q, want \"\" (caller keeps using its existing go binary)", goBin)
+	}
+
+	content, err := os.ReadFile(modPath)
+	if err != nil {
+		t.Fatalf("reading go.mod: %v", err)
+	}
+	if !contains(string(content), "toolchain go1.20") {
+		t.Errorf("go.mod = %q, want it to contain a toolchain go1.20 directive", content)
+	}
+}
+
+func contains(s, substr string) bool {
+	return len(s) >= len(substr) && (func() bool {
+		for i := 0; i+len(substr) <= len(s); i++ {
+			if s[i:i+len(substr)] == substr {
+				return true
+			}
+		}
+		return false
+	})()
+}